@@ -0,0 +1,127 @@
+package tumblr
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+
+	ap "github.com/micaelAlastor/tumblr.go/activitypub"
+)
+
+// apBase returns the ActivityPub base id for a blog name, e.g.
+// "https://demo.tumblr.com/ap" for the short name "demo". It normalizes the
+// name the same way the REST client does, so a short name given without its
+// ".tumblr.com" suffix doesn't produce a bogus hostname.
+func apBase(blogName string) string {
+	return fmt.Sprintf("https://%s/ap", normalizeBlogName(blogName))
+}
+
+// AsActor converts the Blog into an ActivityPub actor document. The blog's
+// avatar is resolved through GetAvatar, so AsActor performs a network call.
+func (b *Blog) AsActor() (ap.Actor, error) {
+	base := apBase(b.Name)
+	actor := ap.Actor{
+		Context:           ap.Context,
+		Id:                base,
+		Type:              "Person",
+		PreferredUsername: b.Name,
+		Name:              b.Title,
+		Summary:           b.Description,
+		Url:               b.Url,
+		Inbox:             base + "/inbox",
+		Outbox:            base + "/outbox",
+		Followers:         base + "/followers",
+		Following:         base + "/following",
+	}
+	avatar, err := GetAvatar(b.client, b.Name)
+	if err != nil {
+		return actor, err
+	}
+	actor.Icon = &ap.Image{Type: "Image", Url: avatar}
+	return actor, nil
+}
+
+// AsNote converts the Post's NPF content, trail and tags into an
+// ActivityStreams Note object.
+func (p *Post) AsNote() ap.Object {
+	note := ap.Object{
+		Context:      ap.Context,
+		Id:           p.PostUrl,
+		Type:         "Note",
+		AttributedTo: apBase(p.BlogName),
+		Url:          p.PostUrl,
+		Content:      renderNpfContentAsHTML(p.Content),
+	}
+	for _, block := range p.Content {
+		note.Attachment = append(note.Attachment, npfContentAsAttachments(block)...)
+	}
+	for _, trail := range p.Trail {
+		note.Content += renderNpfContentAsHTML(trail.Content)
+	}
+	for _, tag := range p.Tags {
+		note.Tag = append(note.Tag, ap.Tag{Type: "Hashtag", Name: "#" + tag})
+	}
+	for _, block := range p.Content {
+		for _, f := range block.Formatting {
+			if f.Type == "mention" {
+				note.Tag = append(note.Tag, ap.Tag{Type: "Mention", Href: f.Blog.Url, Name: f.Blog.Name})
+			}
+		}
+	}
+	return note
+}
+
+// renderNpfContentAsHTML produces a minimal HTML rendering of a post's text
+// blocks, good enough to populate a Note's content field. Text is escaped so
+// that a post's own content can't inject markup into federated output.
+func renderNpfContentAsHTML(content []NpfContent) string {
+	html := ""
+	for _, block := range content {
+		if block.Type == "text" {
+			html += "<p>" + template.HTMLEscapeString(block.Text) + "</p>"
+		}
+	}
+	return html
+}
+
+// npfContentAsAttachments converts a single NPF content block's media or
+// link into zero or more AS2 attachments.
+func npfContentAsAttachments(block NpfContent) []ap.Attachment {
+	switch block.Type {
+	case "image", "video":
+		media := block.Media.Media
+		if block.Media.IsArray && len(block.Media.MediaCollection) > 0 {
+			media = block.Media.MediaCollection[0]
+		}
+		if media.Url == "" {
+			return nil
+		}
+		return []ap.Attachment{{Type: "Image", MediaType: media.Type, Url: media.Url}}
+	case "link":
+		return []ap.Attachment{{Type: "Link", Url: block.NpfLink.Url, Name: block.NpfLink.Title}}
+	}
+	return nil
+}
+
+// PostsOutbox fetches one page of blogRef's posts, as selected by params, and
+// serializes them as an AS2 OrderedCollection, suitable for serving a
+// federation outbox endpoint. Later pages are not fetched, so TotalItems (the
+// blog's total post count) can exceed the number of OrderedItems.
+func PostsOutbox(blogRef *BlogRef, params url.Values) (ap.OrderedCollection, error) {
+	posts, err := blogRef.GetPosts(params)
+	if err != nil {
+		return ap.OrderedCollection{}, err
+	}
+	actorId := apBase(blogRef.Name)
+	collection := ap.OrderedCollection{
+		Context:    ap.Context,
+		Id:         actorId + "/outbox",
+		Type:       "OrderedCollection",
+		TotalItems: posts.TotalPosts,
+	}
+	for _, post := range posts.Posts {
+		note := post.GetSelf().AsNote()
+		collection.OrderedItems = append(collection.OrderedItems, ap.NewCreate(note.Id+"/activity", actorId, note))
+	}
+	return collection, nil
+}
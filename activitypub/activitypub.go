@@ -0,0 +1,96 @@
+// Package activitypub provides the minimal set of ActivityStreams/ActivityPub
+// types needed to expose Tumblr blogs and posts as federated objects. It is
+// intentionally small: just enough JSON-LD shapes for an actor, a note and an
+// outbox collection, rather than a general-purpose AS2 implementation.
+package activitypub
+
+// Context is the JSON-LD context shared by every object below.
+var Context = []string{
+	"https://www.w3.org/ns/activitystreams",
+	"https://w3id.org/security/v1",
+}
+
+// Image is a minimal representation of an AS2 Image, used for actor icons.
+type Image struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType,omitempty"`
+	Url       string `json:"url"`
+}
+
+// Actor represents an AS2 actor document (e.g. Person or Service).
+type Actor struct {
+	Context           []string `json:"@context"`
+	Id                string   `json:"id"`
+	Type              string   `json:"type"`
+	PreferredUsername string   `json:"preferredUsername"`
+	Name              string   `json:"name,omitempty"`
+	Summary           string   `json:"summary,omitempty"`
+	Url               string   `json:"url,omitempty"`
+	Icon              *Image   `json:"icon,omitempty"`
+	Inbox             string   `json:"inbox"`
+	Outbox            string   `json:"outbox"`
+	Followers         string   `json:"followers,omitempty"`
+	Following         string   `json:"following,omitempty"`
+}
+
+// Tag is an inline AS2 tag, used for hashtags and mentions attached to a Note.
+type Tag struct {
+	Type string `json:"type"`
+	Href string `json:"href,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Attachment represents an AS2 attachment, used for media and link previews.
+type Attachment struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType,omitempty"`
+	Url       string `json:"url"`
+	Name      string `json:"name,omitempty"`
+}
+
+// Object represents an AS2 object, such as a Note.
+type Object struct {
+	Context      []string     `json:"@context,omitempty"`
+	Id           string       `json:"id"`
+	Type         string       `json:"type"`
+	AttributedTo string       `json:"attributedTo"`
+	Url          string       `json:"url,omitempty"`
+	Content      string       `json:"content"`
+	Published    string       `json:"published,omitempty"`
+	To           []string     `json:"to,omitempty"`
+	Cc           []string     `json:"cc,omitempty"`
+	Tag          []Tag        `json:"tag,omitempty"`
+	Attachment   []Attachment `json:"attachment,omitempty"`
+}
+
+// Activity wraps an Object in a Create/Update/Delete activity.
+type Activity struct {
+	Context   []string `json:"@context"`
+	Id        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published,omitempty"`
+	To        []string `json:"to,omitempty"`
+	Cc        []string `json:"cc,omitempty"`
+	Object    Object   `json:"object"`
+}
+
+// OrderedCollection represents a paged AS2 outbox/collection response.
+type OrderedCollection struct {
+	Context      []string      `json:"@context"`
+	Id           string        `json:"id"`
+	Type         string        `json:"type"`
+	TotalItems   int64         `json:"totalItems"`
+	OrderedItems []interface{} `json:"orderedItems"`
+}
+
+// NewCreate wraps obj in a Create activity attributed to actorId.
+func NewCreate(id, actorId string, obj Object) Activity {
+	return Activity{
+		Context: Context,
+		Id:      id,
+		Type:    "Create",
+		Actor:   actorId,
+		Object:  obj,
+	}
+}
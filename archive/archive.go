@@ -0,0 +1,277 @@
+// Package archive exports a Tumblr blog's posts to a directory of Markdown
+// files, alongside an OPML blog list and a JSON post index, mirroring how
+// mastodon-markdown-archive serializes ActivityPub posts to disk.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/micaelAlastor/tumblr.go"
+)
+
+// Options controls how ArchiveBlog paginates and resumes an export.
+type Options struct {
+	// PageSize is the number of posts requested per page. Defaults to 20.
+	PageSize int
+	// ResumeFromId, if set, skips posts at or after this id, allowing an
+	// interrupted export to continue without re-downloading earlier posts.
+	ResumeFromId uint64
+}
+
+// postIndexEntry is one row of the JSON post index written by ArchiveBlog.
+type postIndexEntry struct {
+	Id        uint64   `json:"id"`
+	Url       string   `json:"url"`
+	Timestamp uint64   `json:"timestamp"`
+	Tags      []string `json:"tags"`
+	File      string   `json:"file"`
+}
+
+// ArchiveBlog paginates through name's full post history and writes one
+// Markdown file per post, an OPML blog list and a JSON post index into
+// outDir. It resumes from opts.ResumeFromId so an interrupted export can be
+// restarted without re-writing already-archived posts.
+func ArchiveBlog(ctx context.Context, client tumblr.ClientInterface, name, outDir string, opts Options) error {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 20
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	blog, err := tumblr.GetBlogInfo(client, name)
+	if err != nil {
+		return err
+	}
+
+	var index []postIndexEntry
+	offset := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		params := url.Values{}
+		params.Set("npf", "true")
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("limit", strconv.Itoa(opts.PageSize))
+		posts, err := tumblr.GetPosts(client, name, params)
+		if err != nil {
+			return err
+		}
+		if len(posts.Posts) == 0 {
+			break
+		}
+		for _, postRef := range posts.Posts {
+			post := postRef.GetSelf()
+			if opts.ResumeFromId != 0 && post.Id >= opts.ResumeFromId {
+				continue
+			}
+			fileName := fmt.Sprintf("%d.md", post.Id)
+			if err := writePostMarkdown(outDir, fileName, post, blog.UUID); err != nil {
+				return err
+			}
+			index = append(index, postIndexEntry{
+				Id:        post.Id,
+				Url:       post.PostUrl,
+				Timestamp: post.Timestamp,
+				Tags:      post.Tags,
+				File:      fileName,
+			})
+		}
+		offset += len(posts.Posts)
+		if int64(offset) >= posts.TotalPosts {
+			break
+		}
+	}
+
+	if err := writeOPML(outDir, blog); err != nil {
+		return err
+	}
+	return writeIndex(outDir, index)
+}
+
+// writePostMarkdown renders a single post to fileName inside outDir, with
+// YAML front-matter followed by its NPF content and trail rendered to
+// Markdown.
+func writePostMarkdown(outDir, fileName string, post *tumblr.Post, blogUUID string) error {
+	var body strings.Builder
+	body.WriteString("---\n")
+	fmt.Fprintf(&body, "id: %d\n", post.Id)
+	fmt.Fprintf(&body, "url: %q\n", post.PostUrl)
+	fmt.Fprintf(&body, "timestamp: %d\n", post.Timestamp)
+	fmt.Fprintf(&body, "blog_uuid: %q\n", blogUUID)
+	fmt.Fprintf(&body, "note_count: %d\n", post.NoteCount)
+	if len(post.Tags) > 0 {
+		body.WriteString("tags:\n")
+		for _, tag := range post.Tags {
+			fmt.Fprintf(&body, "  - %q\n", tag)
+		}
+	}
+	body.WriteString("---\n\n")
+
+	body.WriteString(renderNpfMarkdown(post.Content, outDir))
+
+	// Each trail entry is nested one level deeper than the last, so the
+	// rendered blockquotes mirror the reblog chain instead of reading as a
+	// flat run of same-depth quotes.
+	for i, trail := range post.Trail {
+		body.WriteString("\n")
+		prefix := strings.Repeat("> ", i+1)
+		quoted := renderNpfMarkdown(trail.Content, outDir)
+		for _, line := range strings.Split(strings.TrimRight(quoted, "\n"), "\n") {
+			body.WriteString(prefix + line + "\n")
+		}
+	}
+
+	return os.WriteFile(filepath.Join(outDir, fileName), []byte(body.String()), 0o644)
+}
+
+// renderNpfMarkdown turns a sequence of NPF content blocks into Markdown,
+// downloading image media next to the archive file as it goes.
+func renderNpfMarkdown(content []tumblr.NpfContent, outDir string) string {
+	var out strings.Builder
+	for _, block := range content {
+		switch block.Type {
+		case "text":
+			out.WriteString(textBlockPrefix(block.Subtype))
+			out.WriteString(applyFormatting(block.Text, block.Formatting))
+			out.WriteString("\n\n")
+		case "image":
+			media := block.Media.Media
+			if block.Media.IsArray && len(block.Media.MediaCollection) > 0 {
+				media = block.Media.MediaCollection[0]
+			}
+			localPath, err := downloadMedia(outDir, media.Url)
+			if err != nil {
+				localPath = media.Url
+			}
+			fmt.Fprintf(&out, "![%s](%s)\n\n", block.AltText, localPath)
+		case "link":
+			fmt.Fprintf(&out, "> [%s](%s)\n>\n> %s\n\n", block.NpfLink.Title, block.NpfLink.Url, block.NpfLink.Description)
+		}
+	}
+	return out.String()
+}
+
+// textBlockPrefix maps an NPF text subtype to its Markdown prefix.
+func textBlockPrefix(subtype string) string {
+	switch subtype {
+	case "heading1":
+		return "# "
+	case "heading2":
+		return "## "
+	case "quote":
+		return "> "
+	case "chat":
+		return "`"
+	case "ordered-list-item":
+		return "1. "
+	case "unordered-list-item":
+		return "- "
+	case "indented":
+		return "\t"
+	}
+	return ""
+}
+
+// applyFormatting wraps text's formatted ranges in Markdown bold/italic/
+// strikethrough/link markers via tumblr.RenderFormattedRuns, the same
+// boundary-walking implementation the HTML renderer uses, so overlapping
+// ranges (e.g. italic nested inside bold) stay well-formed here too.
+func applyFormatting(text string, formatting []tumblr.Formatting) string {
+	if len(formatting) == 0 {
+		return text
+	}
+
+	var out strings.Builder
+	tumblr.RenderFormattedRuns(text, formatting, func(f tumblr.Formatting) {
+		switch f.Type {
+		case "bold":
+			out.WriteString("**")
+		case "italic":
+			out.WriteString("*")
+		case "strikethrough":
+			out.WriteString("~~")
+		case "link", "mention":
+			out.WriteString("[")
+		}
+	}, func(f tumblr.Formatting) {
+		switch f.Type {
+		case "bold":
+			out.WriteString("**")
+		case "italic":
+			out.WriteString("*")
+		case "strikethrough":
+			out.WriteString("~~")
+		case "link":
+			fmt.Fprintf(&out, "](%s)", f.Url)
+		case "mention":
+			fmt.Fprintf(&out, "](%s)", f.Blog.Url)
+		}
+	}, func(run string) {
+		out.WriteString(run)
+	})
+	return out.String()
+}
+
+// downloadMedia fetches mediaUrl and writes it next to the archive file,
+// returning the path to use in the rendered Markdown.
+func downloadMedia(outDir, mediaUrl string) (string, error) {
+	if mediaUrl == "" {
+		return "", fmt.Errorf("empty media url")
+	}
+	resp, err := http.Get(mediaUrl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	fileName := filepath.Join("media", filepath.Base(mediaUrl))
+	fullPath := filepath.Join(outDir, fileName)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return "", err
+	}
+	out, err := os.Create(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+// writeOPML writes an OPML outline listing the archived blog.
+func writeOPML(outDir string, blog *tumblr.Blog) error {
+	opml := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>Tumblr archive</title>
+  </head>
+  <body>
+    <outline text=%q title=%q type="rss" xmlUrl=%q htmlUrl=%q/>
+  </body>
+</opml>
+`, blog.Title, blog.Title, blog.Url+"/rss", blog.Url)
+	return os.WriteFile(filepath.Join(outDir, "blogs.opml"), []byte(opml), 0o644)
+}
+
+// writeIndex writes the JSON post index used to resume a later export.
+func writeIndex(outDir string, index []postIndexEntry) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "index.json"), data, 0o644)
+}
@@ -0,0 +1,41 @@
+package archive
+
+import (
+	"testing"
+
+	"github.com/micaelAlastor/tumblr.go"
+)
+
+func TestApplyFormattingOverlappingRanges(t *testing.T) {
+	// "hello world" with bold over [0,11) and italic nested inside it over
+	// [6,11) ("world"). The nested range must stay inside the outer one.
+	text := "hello world"
+	formatting := []tumblr.Formatting{
+		{Type: "bold", Start: 0, End: 11},
+		{Type: "italic", Start: 6, End: 11},
+	}
+
+	got := applyFormatting(text, formatting)
+	want := "**hello *world***"
+	if got != want {
+		t.Errorf("applyFormatting(%q, overlapping) = %q, want %q", text, got, want)
+	}
+}
+
+func TestApplyFormattingLink(t *testing.T) {
+	formatting := []tumblr.Formatting{
+		{Type: "link", Start: 0, End: 5, Url: "https://example.com"},
+	}
+	got := applyFormatting("hello", formatting)
+	want := "[hello](https://example.com)"
+	if got != want {
+		t.Errorf("applyFormatting(link) = %q, want %q", got, want)
+	}
+}
+
+func TestApplyFormattingNoRanges(t *testing.T) {
+	got := applyFormatting("plain text", nil)
+	if got != "plain text" {
+		t.Errorf("applyFormatting(no ranges) = %q, want unchanged text", got)
+	}
+}
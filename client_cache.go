@@ -0,0 +1,46 @@
+package tumblr
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// SetHTTPClient and SetDefaultHeaders are declared on *Client, the concrete
+// ClientInterface implementation that owns the Get/GetWithParams methods
+// queryPosts, GetBlogInfo and GetAvatar funnel through; that type and its
+// request-building code live in client.go, outside this chunk. Get and
+// GetWithParams are expected to build each *http.Request through c.httpClient
+// (falling back to http.DefaultClient when nil) and set c.defaultHeaders on
+// it before sending, so a caching RoundTripper and cache-control override
+// installed here actually take effect on every outbound request.
+
+// SetHTTPClient overrides the http.Client used for outbound requests. Pass a
+// client built around a caching http.RoundTripper (e.g. httpcache) to avoid
+// re-fetching unchanged responses and cut into Tumblr's per-hour API quota.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetDefaultHeaders sets headers to be sent on every outbound request, such
+// as a "Cache-Control: max-stale=..." override to force a caching transport
+// to serve slightly stale responses rather than revalidating.
+func (c *Client) SetDefaultHeaders(headers map[string]string) {
+	c.defaultHeaders = headers
+}
+
+// WithCacheKey normalizes params so that requests differing only in query
+// parameter ordering produce the same cache key. It returns a copy of params
+// with each key's values sorted; key order needs no handling here, since
+// url.Values.Encode already emits keys in sorted order. It should be applied
+// before handing params to Get or GetWithParams whenever responses pass
+// through a caching transport.
+func WithCacheKey(params url.Values) url.Values {
+	normalized := url.Values{}
+	for key, values := range params {
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		normalized[key] = sorted
+	}
+	return normalized
+}
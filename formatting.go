@@ -0,0 +1,105 @@
+package tumblr
+
+import "sort"
+
+// RenderFormattedRuns splits text at the start/end offsets of formatting and
+// drives openFn/closeFn/textFn over the result, maintaining a stack of
+// currently-open ranges so a range already open across a boundary is never
+// closed and reopened again. This keeps output well-formed even when markup
+// markers aren't distinguishable as open/close tokens (Markdown's "**" reads
+// the same both ways) — re-wrapping every active range at every boundary
+// would otherwise splice adjacent identical markers into an ambiguous run.
+//
+// openFn and closeFn are called, in properly-nested order, only for ranges
+// that actually start or end at the current boundary; closeFn fires
+// innermost-first. textFn is called once per run with the literal text
+// between boundaries. This assumes formatting ranges are non-crossing
+// (nested or disjoint), which is how Tumblr's NPF formatting is structured.
+func RenderFormattedRuns(text string, formatting []Formatting, openFn, closeFn func(Formatting), textFn func(string)) {
+	runes := []rune(text)
+	length := len(runes)
+	if length == 0 {
+		return
+	}
+
+	ranges := make([]Formatting, 0, len(formatting))
+	for _, f := range formatting {
+		start, end := clampRange(f.Start, f.End, length)
+		if start < end {
+			f.Start, f.End = start, end
+			ranges = append(ranges, f)
+		}
+	}
+	if len(ranges) == 0 {
+		textFn(string(runes))
+		return
+	}
+
+	// Outer ranges first, so active sets below stay in nesting order.
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Start != ranges[j].Start {
+			return ranges[i].Start < ranges[j].Start
+		}
+		return ranges[i].End > ranges[j].End
+	})
+
+	boundarySet := map[int]bool{0: true, length: true}
+	for _, f := range ranges {
+		boundarySet[f.Start] = true
+		boundarySet[f.End] = true
+	}
+	boundaries := make([]int, 0, len(boundarySet))
+	for b := range boundarySet {
+		boundaries = append(boundaries, b)
+	}
+	sort.Ints(boundaries)
+
+	var active []int
+	for i := 0; i+1 < len(boundaries); i++ {
+		segStart, segEnd := boundaries[i], boundaries[i+1]
+		if segStart >= segEnd {
+			continue
+		}
+
+		var next []int
+		for idx, f := range ranges {
+			if f.Start <= segStart && f.End >= segEnd {
+				next = append(next, idx)
+			}
+		}
+
+		common := 0
+		for common < len(active) && common < len(next) && active[common] == next[common] {
+			common++
+		}
+		for j := len(active) - 1; j >= common; j-- {
+			closeFn(ranges[active[j]])
+		}
+		for j := common; j < len(next); j++ {
+			openFn(ranges[next[j]])
+		}
+
+		textFn(string(runes[segStart:segEnd]))
+		active = next
+	}
+	for j := len(active) - 1; j >= 0; j-- {
+		closeFn(ranges[active[j]])
+	}
+}
+
+// clampRange restricts [start,end) to [0,length].
+func clampRange(start, end, length int) (int, int) {
+	if start < 0 {
+		start = 0
+	}
+	if start > length {
+		start = length
+	}
+	if end > length {
+		end = length
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
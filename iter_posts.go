@@ -0,0 +1,209 @@
+package tumblr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PostsCursor is an opaque, serializable checkpoint into a blog's post
+// history. Encode it after processing a page so a long-running export can
+// resume later without re-walking already-seen posts.
+type PostsCursor struct {
+	Offset int        `json:"offset"`
+	Params url.Values `json:"params"`
+}
+
+// Encode renders the cursor as an opaque string suitable for storing
+// alongside a partially-completed export.
+func (c PostsCursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a string produced by PostsCursor.Encode.
+func DecodeCursor(s string) (PostsCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return PostsCursor{}, err
+	}
+	var cursor PostsCursor
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// IterOptions tunes how IterPosts and PostsChannel page through a blog's
+// history.
+type IterOptions struct {
+	// PageSize is the number of posts requested per page. Defaults to 20.
+	PageSize int
+	// Delay is the minimum time to wait between successive page requests.
+	Delay time.Duration
+	// MaxBackoff caps the exponential backoff applied after a 429/5xx
+	// response. Defaults to one minute.
+	MaxBackoff time.Duration
+	// Cursor, if non-zero, resumes paging from a previously-returned
+	// PostsCursor instead of starting from the beginning.
+	Cursor PostsCursor
+}
+
+// httpStatusError is implemented by client errors that carry the response's
+// HTTP status code, letting the paging loop distinguish a rate limit or
+// server error (worth retrying) from a permanent failure. ClientInterface
+// implementations that don't already return an error satisfying this
+// interface for non-2xx responses can wrap it in a StatusError so the
+// backoff in walkPostPages engages.
+type httpStatusError interface {
+	StatusCode() int
+}
+
+// StatusError wraps an error with the HTTP status code that produced it.
+// Use it to adapt a ClientInterface implementation whose errors don't
+// already implement httpStatusError, so 429/5xx responses are recognized
+// as retryable by IterPosts and PostsChannel.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StatusError) StatusCode() int {
+	return e.Code
+}
+
+func isRetryableStatus(err error) bool {
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode()
+		return code == 429 || code >= 500
+	}
+	return false
+}
+
+// PostResult pairs a single post with the cursor to resume immediately after
+// it, for use with PostsChannel.
+type PostResult struct {
+	Post   *Post
+	Cursor PostsCursor
+	Err    error
+}
+
+// PostsChannel is the pre-Go-1.23 channel-based equivalent of IterPosts. It
+// pages through blogRef's post history in a goroutine, sending one
+// PostResult per post, and stops once ctx is cancelled or the history is
+// exhausted.
+func (b *BlogRef) PostsChannel(ctx context.Context, params url.Values, opts IterOptions) <-chan PostResult {
+	out := make(chan PostResult)
+	go func() {
+		defer close(out)
+		walkPostPages(ctx, b, params, opts, func(post *Post, cursor PostsCursor) bool {
+			select {
+			case out <- PostResult{Post: post, Cursor: cursor}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}, func(err error) {
+			select {
+			case out <- PostResult{Err: err}:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return out
+}
+
+// ResumePostsChannel continues a PostsChannel export from a previously
+// captured cursor.
+func (b *BlogRef) ResumePostsChannel(ctx context.Context, cursor PostsCursor) <-chan PostResult {
+	opts := IterOptions{Cursor: cursor}
+	return b.PostsChannel(ctx, cursor.Params, opts)
+}
+
+// walkPostPages is the shared paging loop used by both IterPosts and
+// PostsChannel: it advances by offset, honors ctx cancellation,
+// sleeps opts.Delay between pages and backs off exponentially on 429/5xx
+// errors up to opts.MaxBackoff.
+func walkPostPages(ctx context.Context, blogRef *BlogRef, params url.Values, opts IterOptions, yield func(post *Post, cursor PostsCursor) bool, onError func(error)) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 20
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	pageParams := url.Values{}
+	for k, v := range params {
+		pageParams[k] = v
+	}
+	offset := opts.Cursor.Offset
+
+	initialBackoff := time.Second
+	if initialBackoff > opts.MaxBackoff {
+		initialBackoff = opts.MaxBackoff
+	}
+	backoff := initialBackoff
+	for {
+		if err := ctx.Err(); err != nil {
+			onError(err)
+			return
+		}
+
+		pageParams.Set("offset", strconv.Itoa(offset))
+		pageParams.Set("limit", strconv.Itoa(opts.PageSize))
+		posts, err := blogRef.GetPosts(pageParams)
+		if err != nil {
+			if isRetryableStatus(err) {
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					onError(ctx.Err())
+					return
+				}
+				if backoff *= 2; backoff > opts.MaxBackoff {
+					backoff = opts.MaxBackoff
+				}
+				continue
+			}
+			onError(err)
+			return
+		}
+		backoff = initialBackoff
+
+		if len(posts.Posts) == 0 {
+			return
+		}
+		for _, post := range posts.Posts {
+			offset++
+			cursor := PostsCursor{Offset: offset, Params: params}
+			if !yield(post.GetSelf(), cursor) {
+				return
+			}
+		}
+		if int64(offset) >= posts.TotalPosts {
+			return
+		}
+
+		if opts.Delay > 0 {
+			select {
+			case <-time.After(opts.Delay):
+			case <-ctx.Done():
+				onError(ctx.Err())
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+//go:build go1.23
+
+package tumblr
+
+import (
+	"context"
+	"iter"
+	"net/url"
+)
+
+// IterPosts pages through blogRef's full post history, advancing via
+// offset until the posts are exhausted. It honors ctx cancellation between
+// pages and applies opts.Delay/backoff as configured. Range over it with a
+// for ... range loop:
+//
+//	for post, err := range blogRef.IterPosts(ctx, params, tumblr.IterOptions{}) {
+//		if err != nil { ... }
+//	}
+func (b *BlogRef) IterPosts(ctx context.Context, params url.Values, opts IterOptions) iter.Seq2[*Post, error] {
+	return func(yield func(*Post, error) bool) {
+		walkPostPages(ctx, b, params, opts, func(post *Post, cursor PostsCursor) bool {
+			return yield(post, nil)
+		}, func(err error) {
+			yield(nil, err)
+		})
+	}
+}
+
+// ResumePosts continues IterPosts from a previously captured PostsCursor.
+func (b *BlogRef) ResumePosts(ctx context.Context, cursor PostsCursor) iter.Seq2[*Post, error] {
+	return b.IterPosts(ctx, cursor.Params, IterOptions{Cursor: cursor})
+}
+
+// IterPostsWithCursor is IterPosts for callers that need to checkpoint a
+// long-running export: it yields the same posts, but paired with the
+// PostsCursor to resume immediately after the post just yielded, same as
+// PostResult.Cursor from PostsChannel. Use it instead of IterPosts whenever
+// the loop might break before the history is exhausted.
+//
+//	for result, err := range blogRef.IterPostsWithCursor(ctx, params, tumblr.IterOptions{}) {
+//		if err != nil { ... }
+//		if shouldStop(result.Post) {
+//			resumeToken, _ := result.Cursor.Encode()
+//			break
+//		}
+//	}
+func (b *BlogRef) IterPostsWithCursor(ctx context.Context, params url.Values, opts IterOptions) iter.Seq2[PostResult, error] {
+	return func(yield func(PostResult, error) bool) {
+		walkPostPages(ctx, b, params, opts, func(post *Post, cursor PostsCursor) bool {
+			return yield(PostResult{Post: post, Cursor: cursor}, nil)
+		}, func(err error) {
+			yield(PostResult{}, err)
+		})
+	}
+}
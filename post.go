@@ -12,8 +12,41 @@ import (
 type Posts struct {
 	client     ClientInterface
 	response   Response
-	Posts      []Post `json:"posts"`
-	TotalPosts int64  `json:"total_posts"`
+	Posts      []PostInterface `json:"posts"`
+	TotalPosts int64           `json:"total_posts"`
+}
+
+// UnmarshalJSON peeks at each post's "type" field and dispatches it into the
+// matching concrete post struct, so Posts.Posts can hold a mix of post types.
+func (p *Posts) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Posts      []json.RawMessage `json:"posts"`
+		TotalPosts int64             `json:"total_posts"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.TotalPosts = raw.TotalPosts
+	p.Posts = make([]PostInterface, 0, len(raw.Posts))
+	for _, postData := range raw.Posts {
+		post, err := unmarshalPost(postData)
+		if err != nil {
+			return err
+		}
+		p.Posts = append(p.Posts, post)
+	}
+	return nil
+}
+
+// Photos returns the subset of p.Posts that are photo posts.
+func (p *Posts) Photos() []*PhotoPost {
+	var photos []*PhotoPost
+	for _, post := range p.Posts {
+		if photo, ok := post.(*PhotoPost); ok {
+			photos = append(photos, photo)
+		}
+	}
+	return photos
 }
 
 // MiniPost stores the basics for what is needed in a Post.
@@ -123,6 +156,9 @@ type NpfLink struct {
 
 type Formatting struct {
 	Type string `json:"type"`
+	//start/end are rune offsets into the owning NpfContent.Text this range applies to
+	Start int `json:"start"`
+	End   int `json:"end"`
 	//for link type formatting
 	Url string `json:"url"`
 	//for mention type formatting
@@ -180,6 +216,16 @@ type BrokenBlog struct {
 	Name string `json:"name"`
 }
 
+// PostInterface is implemented by every concrete post type (TextPost,
+// PhotoPost, QuotePost, ...) as well as the shared Post itself, so callers
+// that don't care about the concrete type can still look up properties and
+// reach the underlying Post.
+type PostInterface interface {
+	GetProperty(key string) (interface{}, error)
+	GetSelf() *Post
+	SetClient(c ClientInterface)
+}
+
 // String returns the Post as a JSON string.
 func (p *Post) String() string {
 	return jsonStringify(*p)
@@ -200,7 +246,7 @@ func (p *Post) GetSelf() *Post {
 
 // helper method for querying a given path which should return a list of posts
 func queryPosts(client ClientInterface, path, name string, params url.Values) (*Posts, error) {
-	response, err := client.GetWithParams(blogPath(path, name), params)
+	response, err := client.GetWithParams(blogPath(path, name), WithCacheKey(params))
 	if err != nil {
 		return nil, err
 	}
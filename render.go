@@ -0,0 +1,244 @@
+package tumblr
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// RenderOptions controls how RenderNPF turns NPF content into HTML.
+type RenderOptions struct {
+	// TargetWidth picks the closest-matching NpfMedia rendition for image
+	// and poster blocks. Zero picks the largest available rendition.
+	TargetWidth int
+	// DisableExternalImages skips emitting <img>/<figure> markup for image,
+	// video and audio blocks, useful when rendering into a context that
+	// shouldn't load third-party media.
+	DisableExternalImages bool
+	// Sanitizer is applied to the rendered HTML before it's returned.
+	// Defaults to defaultSanitizer(), a UGCPolicy extended to allow the
+	// srcset and class attributes this renderer emits.
+	Sanitizer *bluemonday.Policy
+}
+
+func (o RenderOptions) sanitizer() *bluemonday.Policy {
+	if o.Sanitizer != nil {
+		return o.Sanitizer
+	}
+	return defaultSanitizer()
+}
+
+// defaultSanitizer is bluemonday's UGCPolicy with the additional attributes
+// this renderer relies on allow-listed: UGCPolicy strips srcset from <img>
+// and class from every element, which would otherwise silently drop the
+// responsive image markup and the "mention"/"link-card"/"chat"/"indented"
+// classes this file emits.
+func defaultSanitizer() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("srcset").OnElements("img")
+	policy.AllowAttrs("class").OnElements("a", "p")
+	return policy
+}
+
+// RenderHTML renders the post's NPF content and trail to sanitized HTML.
+func (p *Post) RenderHTML(opts RenderOptions) (template.HTML, error) {
+	return RenderNPF(p.Content, p.Trail, opts)
+}
+
+// RenderNPF turns a post's NPF content blocks, and any trail of reblogged
+// posts, into sanitized HTML. Trail entries are rendered as nested
+// <blockquote> chains, innermost (oldest) last.
+func RenderNPF(content []NpfContent, trail []NpfTrail, opts RenderOptions) (template.HTML, error) {
+	var html strings.Builder
+	renderNpfBlocks(&html, content, opts)
+
+	// Each trail entry's <blockquote> wraps the rest of the chain, so the
+	// markup nests one level deeper per reblog instead of producing a flat
+	// run of sibling blockquotes.
+	for _, t := range trail {
+		html.WriteString("<blockquote>")
+		switch {
+		case t.Blog.Name != "":
+			fmt.Fprintf(&html, "<p><a href=\"%s\">%s</a> said:</p>",
+				template.HTMLEscapeString(t.Blog.Url), template.HTMLEscapeString(t.Blog.Name))
+		case t.BrokenBlog.Name != "":
+			fmt.Fprintf(&html, "<p>%s said (blog unavailable):</p>", template.HTMLEscapeString(t.BrokenBlog.Name))
+		}
+		renderNpfBlocks(&html, t.Content, opts)
+	}
+	for range trail {
+		html.WriteString("</blockquote>")
+	}
+
+	return template.HTML(opts.sanitizer().Sanitize(html.String())), nil
+}
+
+func renderNpfBlocks(w *strings.Builder, blocks []NpfContent, opts RenderOptions) {
+	for i := 0; i < len(blocks); {
+		block := blocks[i]
+		if block.Type == "text" && isListItemSubtype(block.Subtype) {
+			i = renderListRun(w, blocks, i)
+			continue
+		}
+		switch block.Type {
+		case "text":
+			open, close := textBlockTags(block.Subtype)
+			w.WriteString(open)
+			w.WriteString(renderFormattedText(block.Text, block.Formatting))
+			w.WriteString(close)
+		case "image":
+			renderMediaBlock(w, block.Media, block.AltText, opts)
+		case "link":
+			renderLinkBlock(w, block.NpfLink)
+		case "video", "audio":
+			renderMediaBlock(w, block.Poster, "", opts)
+		}
+		i++
+	}
+}
+
+func isListItemSubtype(subtype string) bool {
+	return subtype == "ordered-list-item" || subtype == "unordered-list-item"
+}
+
+// renderListRun wraps a contiguous run of list-item blocks sharing the same
+// subtype in a single <ol>/<ul>, starting at index i, and returns the index
+// of the first block after the run.
+func renderListRun(w *strings.Builder, blocks []NpfContent, i int) int {
+	subtype := blocks[i].Subtype
+	listTag := "ul"
+	if subtype == "ordered-list-item" {
+		listTag = "ol"
+	}
+	fmt.Fprintf(w, "<%s>", listTag)
+	for i < len(blocks) && blocks[i].Type == "text" && blocks[i].Subtype == subtype {
+		w.WriteString("<li>")
+		w.WriteString(renderFormattedText(blocks[i].Text, blocks[i].Formatting))
+		w.WriteString("</li>")
+		i++
+	}
+	fmt.Fprintf(w, "</%s>", listTag)
+	return i
+}
+
+func textBlockTags(subtype string) (open, close string) {
+	switch subtype {
+	case "heading1":
+		return "<h1>", "</h1>"
+	case "heading2":
+		return "<h2>", "</h2>"
+	case "quote":
+		return "<blockquote>", "</blockquote>"
+	case "chat":
+		return "<p class=\"chat\">", "</p>"
+	case "indented":
+		return "<p class=\"indented\">", "</p>"
+	default:
+		return "<p>", "</p>"
+	}
+}
+
+// renderFormattedText applies text's Formatting ranges via RenderFormattedRuns,
+// emitting the HTML tags each range type maps to. Formatting ranges are
+// expected to nest or be disjoint, never cross, matching NPF's model.
+func renderFormattedText(text string, formatting []Formatting) string {
+	if len(formatting) == 0 {
+		return template.HTMLEscapeString(text)
+	}
+
+	var out strings.Builder
+	RenderFormattedRuns(text, formatting, func(f Formatting) {
+		switch f.Type {
+		case "bold":
+			out.WriteString("<b>")
+		case "italic":
+			out.WriteString("<i>")
+		case "strikethrough":
+			out.WriteString("<s>")
+		case "link":
+			fmt.Fprintf(&out, "<a href=\"%s\">", htmlAttr(f.Url))
+		case "mention":
+			fmt.Fprintf(&out, "<a class=\"mention\" href=\"%s\">", htmlAttr(f.Blog.Url))
+		}
+	}, func(f Formatting) {
+		switch f.Type {
+		case "bold":
+			out.WriteString("</b>")
+		case "italic":
+			out.WriteString("</i>")
+		case "strikethrough":
+			out.WriteString("</s>")
+		case "link", "mention":
+			out.WriteString("</a>")
+		}
+	}, func(run string) {
+		out.WriteString(template.HTMLEscapeString(run))
+	})
+	return out.String()
+}
+
+// renderMediaBlock picks the NpfMedia closest to opts.TargetWidth from
+// container and emits it as a <figure><img srcset> tag.
+func renderMediaBlock(w *strings.Builder, container NpfMediaContainer, altText string, opts RenderOptions) {
+	if opts.DisableExternalImages {
+		return
+	}
+	media := pickMedia(container, opts.TargetWidth)
+	if media.Url == "" {
+		return
+	}
+	srcset := media.Url
+	if container.IsArray {
+		parts := make([]string, 0, len(container.MediaCollection))
+		for _, m := range container.MediaCollection {
+			parts = append(parts, fmt.Sprintf("%s %dw", m.Url, m.Width))
+		}
+		srcset = strings.Join(parts, ", ")
+	}
+	fmt.Fprintf(w, "<figure><img src=\"%s\" srcset=\"%s\" alt=\"%s\"></figure>",
+		htmlAttr(media.Url), htmlAttr(srcset), htmlAttr(altText))
+}
+
+// htmlAttr escapes s for safe use inside a double-quoted HTML attribute.
+func htmlAttr(s string) string {
+	return template.HTMLEscapeString(s)
+}
+
+// pickMedia returns the NpfMedia rendition closest to targetWidth, or the
+// largest rendition when targetWidth is zero.
+func pickMedia(container NpfMediaContainer, targetWidth int) NpfMedia {
+	if !container.IsArray {
+		return container.Media
+	}
+	if len(container.MediaCollection) == 0 {
+		return NpfMedia{}
+	}
+	best := container.MediaCollection[0]
+	for _, m := range container.MediaCollection[1:] {
+		if targetWidth <= 0 {
+			if m.Width > best.Width {
+				best = m
+			}
+			continue
+		}
+		if abs(m.Width-targetWidth) < abs(best.Width-targetWidth) {
+			best = m
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// renderLinkBlock renders an NPF link block as an OpenGraph-style card.
+func renderLinkBlock(w *strings.Builder, link NpfLink) {
+	fmt.Fprintf(w, "<a class=\"link-card\" href=\"%s\"><strong>%s</strong><p>%s</p></a>",
+		htmlAttr(link.Url), htmlAttr(link.Title), htmlAttr(link.Description))
+}
@@ -0,0 +1,56 @@
+package tumblr
+
+import "testing"
+
+func TestRenderFormattedTextOverlappingRanges(t *testing.T) {
+	// "hello world" with bold over [0,11) and italic nested inside it over
+	// [6,11) ("world"). The nested range must stay inside the outer one.
+	text := "hello world"
+	formatting := []Formatting{
+		{Type: "bold", Start: 0, End: 11},
+		{Type: "italic", Start: 6, End: 11},
+	}
+
+	got := renderFormattedText(text, formatting)
+	want := "<b>hello <i>world</i></b>"
+	if got != want {
+		t.Errorf("renderFormattedText(%q, overlapping) = %q, want %q", text, got, want)
+	}
+}
+
+func TestRenderFormattedTextEscapesText(t *testing.T) {
+	got := renderFormattedText("<script>", nil)
+	want := "&lt;script&gt;"
+	if got != want {
+		t.Errorf("renderFormattedText did not escape input: got %q, want %q", got, want)
+	}
+}
+
+func TestRenderFormattedTextMention(t *testing.T) {
+	formatting := []Formatting{
+		{Type: "mention", Start: 0, End: 5, Blog: BlogMiniInfo{Url: "https://example.tumblr.com"}},
+	}
+	got := renderFormattedText("hello", formatting)
+	want := `<a class="mention" href="https://example.tumblr.com">hello</a>`
+	if got != want {
+		t.Errorf("renderFormattedText(mention) = %q, want %q", got, want)
+	}
+}
+
+func TestClampRange(t *testing.T) {
+	cases := []struct {
+		start, end, length int
+		wantStart, wantEnd int
+	}{
+		{-5, 10, 10, 0, 10},
+		{2, 20, 10, 2, 10},
+		{5, 2, 10, 5, 5},
+	}
+	for _, c := range cases {
+		gotStart, gotEnd := clampRange(c.start, c.end, c.length)
+		if gotStart != c.wantStart || gotEnd != c.wantEnd {
+			t.Errorf("clampRange(%d, %d, %d) = (%d, %d), want (%d, %d)",
+				c.start, c.end, c.length, gotStart, gotEnd, c.wantStart, c.wantEnd)
+		}
+	}
+}
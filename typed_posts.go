@@ -0,0 +1,137 @@
+package tumblr
+
+import "encoding/json"
+
+// TextPost is a post of type "text". Its body lives on the embedded Post.
+type TextPost struct {
+	Post
+}
+
+// PhotoPost is a post of type "photo", carrying one or more photos.
+type PhotoPost struct {
+	Post
+	Photos         []Photo `json:"photos"`
+	PhotosetLayout string  `json:"photoset_layout,omitempty"`
+}
+
+// Photo is a single image attached to a PhotoPost.
+type Photo struct {
+	Caption      string      `json:"caption"`
+	OriginalSize PhotoSize   `json:"original_size"`
+	AltSizes     []PhotoSize `json:"alt_sizes"`
+}
+
+// PhotoSize is one rendition of a Photo at a given width/height.
+type PhotoSize struct {
+	Url    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// QuotePost is a post of type "quote".
+type QuotePost struct {
+	Post
+	Text   string `json:"text"`
+	Source string `json:"source"`
+}
+
+// LinkPost is a post of type "link".
+type LinkPost struct {
+	Post
+	Title     string `json:"title"`
+	Url       string `json:"url"`
+	Author    string `json:"author"`
+	Excerpt   string `json:"excerpt"`
+	Publisher string `json:"publisher"`
+}
+
+// ChatPost is a post of type "chat".
+type ChatPost struct {
+	Post
+	Dialogue []DialogueEntry `json:"dialogue"`
+}
+
+// DialogueEntry is a single line of a ChatPost's dialogue.
+type DialogueEntry struct {
+	Name   string `json:"name"`
+	Label  string `json:"label"`
+	Phrase string `json:"phrase"`
+}
+
+// AudioPost is a post of type "audio".
+type AudioPost struct {
+	Post
+	AudioUrl  string `json:"audio_url"`
+	AudioType string `json:"audio_type"`
+	Plays     uint64 `json:"plays"`
+	AlbumArt  string `json:"album_art"`
+	Artist    string `json:"artist"`
+	Album     string `json:"album"`
+}
+
+// VideoPost is a post of type "video".
+type VideoPost struct {
+	Post
+	Player   []VideoPlayer `json:"player"`
+	VideoUrl string        `json:"video_url"`
+	Duration uint64        `json:"duration"`
+}
+
+// VideoPlayer is one embeddable player size for a VideoPost.
+type VideoPlayer struct {
+	Width     int    `json:"width"`
+	EmbedCode string `json:"embed_code"`
+}
+
+// AnswerPost is a post of type "answer".
+type AnswerPost struct {
+	Post
+	AskingName string `json:"asking_name"`
+	AskingUrl  string `json:"asking_url"`
+	Question   string `json:"question"`
+	Answer     string `json:"answer"`
+}
+
+// GenericPost is used for post types this package doesn't model explicitly,
+// so that upstream API additions don't break decoding.
+type GenericPost struct {
+	Post
+}
+
+// unmarshalPost inspects postData's "type" field and decodes it into the
+// matching concrete post struct, falling back to GenericPost for unknown
+// types.
+func unmarshalPost(postData json.RawMessage) (PostInterface, error) {
+	typePeek := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(postData, &typePeek); err != nil {
+		return nil, err
+	}
+
+	var post PostInterface
+	switch typePeek.Type {
+	case "text":
+		post = &TextPost{}
+	case "photo":
+		post = &PhotoPost{}
+	case "quote":
+		post = &QuotePost{}
+	case "link":
+		post = &LinkPost{}
+	case "chat":
+		post = &ChatPost{}
+	case "audio":
+		post = &AudioPost{}
+	case "video":
+		post = &VideoPost{}
+	case "answer":
+		post = &AnswerPost{}
+	default:
+		post = &GenericPost{}
+	}
+	if err := json.Unmarshal(postData, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}